@@ -0,0 +1,104 @@
+package solitaire
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+// stream adapts a Keystream to the crypto/cipher.Stream interface, the way
+// crypto/blowfish and crypto/twofish expose block ciphers through the
+// standard cipher package. Only A-Z bytes (case-insensitively) participate
+// in the keystream; every other byte is either passed through unchanged or,
+// if passthrough is false, rejected, depending on how the stream was built.
+type stream struct {
+	ks          *Keystream
+	decrypt     bool
+	passthrough bool
+}
+
+// CipherOption configures a stream returned by NewCipher or NewDecrypter.
+type CipherOption func(*stream)
+
+// WithPassthrough controls how bytes outside A-Z (case-insensitively) are
+// handled. With passthrough (the default), they are copied to the output
+// unchanged.
+//
+// WithPassthrough(false) is a strict mode for callers who have already
+// guaranteed src is letters-only and want a hard failure if that invariant
+// is ever violated: XORKeyStream panics on the first non-letter byte it
+// sees, rather than silently skipping or mangling it -- cipher.Stream's
+// fixed-length dst/src contract leaves no way to actually shrink the
+// output, so there is no non-panicking way to drop a byte mid-stream.
+// Ordinary input is very likely to contain spaces or punctuation, so most
+// callers should leave this at the default; only disable it once src is
+// already known to be clean, uppercase-or-lowercase A-Z text.
+func WithPassthrough(passthrough bool) CipherOption {
+	return func(s *stream) { s.passthrough = passthrough }
+}
+
+// NewCipher returns a cipher.Stream that encrypts src using the Solitaire
+// algorithm keyed with key: XORKeyStream walks src one byte at a time, and
+// for each A-Z byte (upper or lower case) adds the next keystream letter to
+// it modulo 26, preserving case. This stream is encrypt-only -- Solitaire
+// combines by addition rather than XOR, so running it again over its own
+// output does not recover the original text; use NewDecrypter, or the
+// Decrypt function, to decrypt. It returns an error if key contains no
+// usable letters.
+func NewCipher(key []byte, opts ...CipherOption) (cipher.Stream, error) {
+	return newCipher(key, false, opts)
+}
+
+// NewDecrypter returns the decrypting counterpart to NewCipher: it subtracts
+// the keystream instead of adding it, so NewDecrypter(key) run over the
+// output of NewCipher(key) recovers the original text.
+func NewDecrypter(key []byte, opts ...CipherOption) (cipher.Stream, error) {
+	return newCipher(key, true, opts)
+}
+
+func newCipher(key []byte, decrypt bool, opts []CipherOption) (cipher.Stream, error) {
+	if len(filter(key)) == 0 {
+		return nil, errors.New("solitaire: key must contain at least one letter")
+	}
+
+	s := &stream{ks: NewKeystream(key), decrypt: decrypt, passthrough: true}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// XORKeyStream implements cipher.Stream. len(dst) must be >= len(src).
+func (s *stream) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("solitaire: output smaller than input")
+	}
+
+	for i, c := range src {
+		var upper byte
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = c
+		case c >= 'a' && c <= 'z':
+			upper = c - ('a' - 'A')
+		default:
+			if !s.passthrough {
+				panic("solitaire: non-letter byte with passthrough disabled")
+			}
+			dst[i] = c
+			continue
+		}
+
+		n := upper - 'A'
+		m := s.ks.Next()
+		var out byte
+		if s.decrypt {
+			out = 'A' + (n-m-1+charSize)%charSize
+		} else {
+			out = 'A' + (n+m+1)%charSize
+		}
+		if c >= 'a' && c <= 'z' {
+			out += 'a' - 'A'
+		}
+		dst[i] = out
+	}
+}
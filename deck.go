@@ -0,0 +1,191 @@
+package solitaire
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NewDeckFromNotation parses a deck ordering from standard bridge notation:
+// 52 space-separated cards of the form <rank><suit> (ranks A, 2-10, J, Q, K;
+// suits C, D, H, S for clubs, diamonds, hearts, spades) plus the two jokers
+// written as bare "A" and "B". The notation is case-insensitive and must
+// describe each of the 54 cards exactly once, e.g.:
+//
+//	NewDeckFromNotation("AC 2C 3C ... KS A B")
+func NewDeckFromNotation(s string) (*Deck, error) {
+	fields := strings.Fields(s)
+	order := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := parseCardNotation(f)
+		if err != nil {
+			return nil, err
+		}
+		order[i] = v
+	}
+
+	return deckFromOrder(order)
+}
+
+// String renders the deck in the same notation accepted by
+// NewDeckFromNotation.
+func (dp *Deck) String() string {
+	d := *dp
+	toks := make([]string, len(d))
+	for i, c := range d {
+		toks[i] = cardNotation(c)
+	}
+	return strings.Join(toks, " ")
+}
+
+// cloneDeck returns a deep copy of d, so that using a Deck to encrypt or
+// decrypt doesn't consume the caller's original ordering.
+func cloneDeck(d *Deck) *Deck {
+	cp := make(Deck, len(*d))
+	for i, c := range *d {
+		cc := *c
+		cp[i] = &cc
+	}
+	return &cp
+}
+
+// EncryptWithDeck behaves like Encrypt, but keys the keystream from an
+// explicit deck ordering d instead of a passphrase.
+func EncryptWithDeck(plaintext []byte, d *Deck) []byte {
+	data := filter(plaintext)
+	data = pad(data, 5, 'X')
+	output := make([]byte, len(data))
+
+	ks := &Keystream{d: cloneDeck(d)}
+	for i, c := range data {
+		n := c - 'A'
+		m := ks.Next()
+		output[i] = 'A' + (n+m+1)%charSize
+	}
+
+	return output
+}
+
+// DecryptWithDeck behaves like Decrypt, but keys the keystream from an
+// explicit deck ordering d instead of a passphrase.
+func DecryptWithDeck(ciphertext []byte, d *Deck) []byte {
+	data := filter(ciphertext)
+	output := make([]byte, len(data))
+
+	ks := &Keystream{d: cloneDeck(d)}
+	for i, c := range data {
+		n := c - 'A'
+		m := ks.Next()
+		output[i] = 'A' + (n-m-1+charSize)%charSize
+	}
+
+	return output
+}
+
+// parseCardNotation parses a single bridge-notation token into the card
+// value encoding used by deckFromOrder (1-52 for regular cards, 53/54 for
+// jokers A/B).
+func parseCardNotation(tok string) (int, error) {
+	tok = strings.ToUpper(tok)
+
+	switch tok {
+	case "A":
+		return suitSize*suitCount + 1, nil
+	case "B":
+		return suitSize*suitCount + 2, nil
+	}
+
+	if len(tok) < 2 {
+		return 0, fmt.Errorf("solitaire: invalid card notation %q", tok)
+	}
+
+	si, err := suitIndex(tok[len(tok)-1])
+	if err != nil {
+		return 0, err
+	}
+	rank, err := parseRank(tok[:len(tok)-1])
+	if err != nil {
+		return 0, err
+	}
+	return si*suitSize + rank, nil
+}
+
+// suitIndex maps a bridge suit letter (C, D, H, S) to its 0-based index in
+// the clubs, diamonds, hearts, spades ordering used throughout this package.
+func suitIndex(c byte) (int, error) {
+	switch c {
+	case 'C':
+		return 0, nil
+	case 'D':
+		return 1, nil
+	case 'H':
+		return 2, nil
+	case 'S':
+		return 3, nil
+	default:
+		return 0, fmt.Errorf("solitaire: invalid suit %q", string(c))
+	}
+}
+
+// parseRank parses a bridge rank (A, 2-10, J, Q, K) into its 1-13 value.
+func parseRank(s string) (int, error) {
+	switch s {
+	case "A":
+		return 1, nil
+	case "J":
+		return 11, nil
+	case "Q":
+		return 12, nil
+	case "K":
+		return 13, nil
+	}
+
+	rank, err := strconv.Atoi(s)
+	if err != nil || rank < 2 || rank > 10 {
+		return 0, fmt.Errorf("solitaire: invalid rank %q", s)
+	}
+	return rank, nil
+}
+
+// rankNotation is the inverse of parseRank.
+func rankNotation(rank int) string {
+	switch rank {
+	case 1:
+		return "A"
+	case 11:
+		return "J"
+	case 12:
+		return "Q"
+	case 13:
+		return "K"
+	default:
+		return strconv.Itoa(rank)
+	}
+}
+
+// suitNotation is the inverse of suitIndex.
+func suitNotation(s suit) byte {
+	switch s {
+	case clubs:
+		return 'C'
+	case diamonds:
+		return 'D'
+	case hearts:
+		return 'H'
+	case spades:
+		return 'S'
+	default:
+		return '?'
+	}
+}
+
+// cardNotation is the inverse of parseCardNotation.
+func cardNotation(c *Card) string {
+	if c.suit == joker {
+		if c.rank == jokerA {
+			return "A"
+		}
+		return "B"
+	}
+	return rankNotation(c.rank) + string(suitNotation(c.suit))
+}
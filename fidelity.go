@@ -0,0 +1,88 @@
+package solitaire
+
+import "errors"
+
+// Options configures the behaviour of EncryptString and DecryptString.
+type Options struct {
+	// Fidelity makes EncryptString return a token that records the
+	// original message's non-letter characters, case and length, so
+	// DecryptString can restore them exactly.
+	Fidelity bool
+}
+
+// EncryptString encrypts s with key using the Solitaire algorithm, the same
+// way Encrypt does, and returns the resulting ciphertext as a string.
+//
+// If opts.Fidelity is set, it additionally returns an opaque token that
+// records every position in s that isn't a plain A-Z/a-z letter (spaces,
+// punctuation, ...) along with the case of every letter. Passing this token
+// to DecryptString restores the original formatting, so
+// DecryptString(EncryptString(s, ...)) == s. Without Fidelity, token is
+// empty and DecryptString returns the filtered, padded, upper-case text
+// Decrypt would have returned.
+func EncryptString(s string, key []byte, opts Options) (ciphertext, token string) {
+	ciphertext = string(Encrypt([]byte(s), key))
+	if opts.Fidelity {
+		token = fidelityMask(s)
+	}
+	return ciphertext, token
+}
+
+// DecryptString decrypts ciphertext with key, the same way Decrypt does,
+// and returns the resulting plaintext as a string.
+//
+// If opts.Fidelity is set and token is non-empty (as returned by
+// EncryptString with opts.Fidelity set), the decrypted letters are
+// re-injected into the recorded positions and case from token, and any
+// trailing X padding added by Encrypt is trimmed automatically. An error is
+// returned if token describes more letters than ciphertext provides.
+func DecryptString(ciphertext string, key []byte, token string, opts Options) (string, error) {
+	pt := Decrypt([]byte(ciphertext), key)
+	if !opts.Fidelity || token == "" {
+		return string(pt), nil
+	}
+	return applyFidelityMask(pt, token)
+}
+
+// fidelityMask builds an opaque token recording the shape of s: every
+// letter is replaced with 'A' or 'a' (marking its original case), and every
+// other byte -- spaces, punctuation, digits -- is kept as-is. The number of
+// 'A'/'a' bytes in the token is the number of letters DecryptString needs
+// to restore s.
+func fidelityMask(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			b[i] = 'A'
+		case c >= 'a' && c <= 'z':
+			b[i] = 'a'
+		}
+	}
+	return string(b)
+}
+
+// applyFidelityMask re-injects pt's upper-case letters into the positions
+// and case recorded by a fidelityMask token, dropping any characters of pt
+// beyond what token calls for (i.e. the Encrypt padding).
+func applyFidelityMask(pt []byte, token string) (string, error) {
+	out := make([]byte, 0, len(token))
+	i := 0
+	for _, c := range []byte(token) {
+		switch c {
+		case 'A', 'a':
+			if i >= len(pt) {
+				return "", errors.New("solitaire: token describes more letters than ciphertext provides")
+			}
+			if c == 'a' {
+				out = append(out, pt[i]+('a'-'A'))
+			} else {
+				out = append(out, pt[i])
+			}
+			i++
+		default:
+			out = append(out, c)
+		}
+	}
+	return string(out), nil
+}
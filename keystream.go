@@ -0,0 +1,81 @@
+package solitaire
+
+import (
+	"fmt"
+	"io"
+)
+
+// Keystream produces the sequence of output cards (Schneier's keystream)
+// generated by repeatedly cycling a keyed deck. It is the building block
+// behind Encrypt, Decrypt and the cipher.Stream implementation in
+// NewCipher.
+type Keystream struct {
+	d *Deck
+
+	// Trace, if non-nil, receives a human-readable dump of the deck
+	// ordering after each of Schneier's five steps, for every call to
+	// Next. This is primarily useful for following the algorithm by hand
+	// or writing golden-file tests against Schneier's worked examples.
+	Trace io.Writer
+}
+
+// NewKeystream keys a fresh deck with the given passphrase and returns the
+// resulting Keystream. Illegal characters ([^a-zA-Z]) in key are skipped and
+// lower case characters are upper-cased, as with Encrypt and Decrypt.
+func NewKeystream(key []byte) *Keystream {
+	d := NewDeck()
+	d.key(filter(key))
+	return &Keystream{d: d}
+}
+
+// NewKeystreamFromOrder builds a Keystream from a caller-supplied deck
+// ordering instead of a passphrase, as described in deckFromOrder. It
+// returns an error if order does not describe a valid 54-card deck.
+func NewKeystreamFromOrder(order []int) (*Keystream, error) {
+	d, err := deckFromOrder(order)
+	if err != nil {
+		return nil, err
+	}
+	return &Keystream{d: d}, nil
+}
+
+// Next performs a single deck cycle (Schneier's steps 1-5) and returns the
+// resulting output card's 0-based character index.
+func (k *Keystream) Next() byte {
+	if k.Trace != nil {
+		return k.nextTraced()
+	}
+	return k.d.Advance(0).number()
+}
+
+// nextTraced is the step-by-step equivalent of (*Deck).Advance(0), used when
+// Trace is set: it performs the same five steps, but dumps the deck
+// ordering to Trace after each one.
+func (k *Keystream) nextTraced() byte {
+	dp := k.d
+
+	a := dp.jokerIndex(jokerA)
+	dp.Move(a, a+1)
+	fmt.Fprintf(k.Trace, "step 1 (move joker A):  %s\n", dp.String())
+
+	b := dp.jokerIndex(jokerB)
+	dp.Move(b, b+2)
+	fmt.Fprintf(k.Trace, "step 2 (move joker B):  %s\n", dp.String())
+
+	dp.TripleCut()
+	fmt.Fprintf(k.Trace, "step 3 (triple cut):    %s\n", dp.String())
+
+	d := *dp
+	i := d[len(d)-1].value()
+	d = dp.CountCut(i)
+	fmt.Fprintf(k.Trace, "step 4 (count cut):     %s\n", dp.String())
+
+	value := d[0].value()
+	output := d[value]
+	if output.suit == joker {
+		fmt.Fprintf(k.Trace, "step 5 (output card):   joker, cycling again\n")
+		return k.nextTraced()
+	}
+	fmt.Fprintf(k.Trace, "step 5 (output card):   %s\n", output.String())
+	return output.number()
+}
@@ -31,12 +31,14 @@ var suits = []suit{clubs, diamonds, hearts, spades}
 
 type suit int
 
-type card struct {
+// Card is a single Solitaire deck card: either a regular playing card
+// (identified by suit and rank) or one of the two jokers.
+type Card struct {
 	suit suit
 	rank int
 }
 
-func (c *card) String() string {
+func (c *Card) String() string {
 	if c.suit == joker {
 		switch c.rank {
 		case jokerA:
@@ -51,7 +53,7 @@ func (c *card) String() string {
 	}
 }
 
-func (c *card) value() int {
+func (c *Card) value() int {
 	if c.suit == joker {
 		return int(c.suit)
 	} else {
@@ -60,35 +62,50 @@ func (c *card) value() int {
 }
 
 // Returns 0-based character index (based on card's value)
-func (c *card) number() byte {
+func (c *Card) number() byte {
 	return byte((c.value() - 1) % charSize)
 }
 
-type deck []*card
+// Deck is an ordering of the 54-card Solitaire deck. Its methods implement
+// the individual operations of Schneier's algorithm (Move, TripleCut,
+// CountCut, Advance) so that callers can drive the algorithm step by step
+// and inspect the deck in between, as well as build the higher-level
+// Encrypt/Decrypt/Keystream machinery in this package.
+type Deck []*Card
 
-// Creates deck of cards with default ordering.
-func newDeck() *deck {
-	d := make(deck, 0, suitSize*suitCount+2)
+// NewDeck creates a deck of cards with default ordering: clubs, diamonds,
+// hearts and spades in turn (A=1 up to K=13 within each suit), followed by
+// joker A and joker B. This is also the ordering assumed by deckFromOrder
+// and NewDeckFromNotation.
+func NewDeck() *Deck {
+	d := make(Deck, 0, suitSize*suitCount+2)
 
 	// Add regular cards
 	for _, s := range suits {
 		for i := 1; i <= suitSize; i++ {
-			d = append(d, &card{s, i})
+			d = append(d, &Card{s, i})
 		}
 	}
 
 	// Add two jokers
-	d = append(d, &card{joker, jokerA})
-	d = append(d, &card{joker, jokerB})
+	d = append(d, &Card{joker, jokerA})
+	d = append(d, &Card{joker, jokerB})
 
-	// for _, c := range deck.cards {
-	// 	fmt.Printf("%s, %d, %d\n", c, c.Value(), c.Number())
-	// }
 	return &d
 }
 
+// Snapshot returns a deep copy of the deck's current card ordering.
+func (dp *Deck) Snapshot() []Card {
+	d := *dp
+	out := make([]Card, len(d))
+	for i, c := range d {
+		out[i] = *c
+	}
+	return out
+}
+
 // Returns index of the specified joker card in this deck
-func (d deck) jokerIndex(rank int) int {
+func (d Deck) jokerIndex(rank int) int {
 	for i, c := range d {
 		if c.suit == joker && c.rank == rank {
 			return i
@@ -97,8 +114,9 @@ func (d deck) jokerIndex(rank int) int {
 	return -1
 }
 
-// Moves card at index i to j (j wraps around and skips bottom card)
-func (d deck) move(i, j int) {
+// Move moves the card at index i to index j (j wraps around and skips the
+// bottom card), as in Schneier's steps 1 and 2.
+func (d Deck) Move(i, j int) {
 	// Recalculate index
 	if j >= len(d) {
 		j = (j % len(d)) + 1
@@ -120,10 +138,32 @@ func (d deck) move(i, j int) {
 	d[j] = card
 }
 
-// Performs a count cut (step 4) at index i
-func (dp *deck) cut(i int) deck {
+// TripleCut performs Schneier's step 3: everything above the topmost joker
+// is swapped with everything below the bottommost one, leaving the jokers
+// and the cards between them in place.
+func (dp *Deck) TripleCut() {
+	d := *dp
+	a, b := dp.jokerIndex(jokerA), dp.jokerIndex(jokerB)
+
+	var top, bot, i int
+	if a < b {
+		top, bot = a, b
+	} else {
+		top, bot = b, a
+	}
+
+	s := make(Deck, len(d))        // Create empty deck
+	i += copy(s, d[bot+1:])        // Copy top segment
+	i += copy(s[i:], d[top:bot+1]) // Copy middle segment
+	i += copy(s[i:], d[:top])      // Copy bottom segment
+	*dp = s
+}
+
+// CountCut performs Schneier's step 4: a cut at index i, with the bottom
+// card staying in place.
+func (dp *Deck) CountCut(i int) Deck {
 	d := *dp
-	s := make(deck, len(d))     // Create empty deck
+	s := make(Deck, len(d))     // Create empty deck
 	copy(s, d[i:])              // Copy top segment
 	copy(s[len(s)-i-1:], d[:i]) // Copy bottom segment
 	s[len(s)-1] = d[len(d)-1]   // Bottom card stays in place
@@ -131,63 +171,40 @@ func (dp *deck) cut(i int) deck {
 	return s
 }
 
-// Generates a single output card
-func (dp *deck) cycle(n int) (output *card) {
-
-	d := *dp
-
-	// fmt.Println("0:", dp)
-
-	// Step 1: move A one card down
-	// 1 2 3 4 ... 52 A B
-	// 1 2 3 4 ... 52 B A
+// Advance performs a single deck cycle -- Schneier's five steps -- and
+// returns the resulting output card. If n is greater than zero, step 4's
+// count cut is repeated using n instead of the bottom card's value, and step 5
+// is skipped; this is how keying the deck from a passphrase (see key)
+// extends an ordinary cycle.
+func (dp *Deck) Advance(n int) (output *Card) {
+	// Step 1: move joker A one card down.
 	a := dp.jokerIndex(jokerA)
-	dp.move(a, a+1)
-	// fmt.Println("1:", dp)
+	dp.Move(a, a+1)
 
-	// Step 2: move B two cards down
-	// 1 2 3 4 ... 52 B A
-	// 1 B 2 3 4 ... 52 A
+	// Step 2: move joker B two cards down.
 	b := dp.jokerIndex(jokerB)
-	dp.move(b, b+2)
-	// fmt.Println("2:", dp)
+	dp.Move(b, b+2)
 
-	// Step 3: perform triple cut
-	// 1 B 2 3 4 ... 52 A
-	// B 2 3 4 ... 52 A 1
-	a, b = dp.jokerIndex(jokerA), dp.jokerIndex(jokerB)
-	var top, bot, i int
-	if a < b {
-		top, bot = a, b
-	} else {
-		top, bot = b, a
-	}
-	s := make(deck, len(d))        // Create empty deck
-	i += copy(s, d[bot+1:])        // Copy top segment
-	i += copy(s[i:], d[top:bot+1]) // Copy middle segment
-	i += copy(s[i:], d[:top])      // Copy bottom segment
-	*dp, d = s, s
-	// fmt.Println("3:", d)
+	// Step 3: triple cut around the two jokers.
+	dp.TripleCut()
 
-	// Step 4: perform count cut
-	// B 2 3 4 ... 52 A 1
-	// 2 3 4 ... 52 A B 1
-	i = d[len(d)-1].value() // Determine cut index from top card
-	d = dp.cut(i)
-	// fmt.Println("4:", dp)
+	// Step 4: count cut on the value of the bottom card.
+	d := *dp
+	i := d[len(d)-1].value()
+	d = dp.CountCut(i)
 
-	// If cut number is given, repeat step 4 with number and skip step 5
+	// If a count is given explicitly, repeat step 4 with it and skip step 5.
 	if n > 0 {
-		dp.cut(n)
-		// fmt.Println("4:", dp)
+		dp.CountCut(n)
 		return
 	}
 
-	// Step 5: find output card
-	value := d[0].value() // Get value of top card
-	output = d[value]     // Determine output card (n steps from top)
+	// Step 5: find the output card n steps from the top, where n is the
+	// value of the (new) top card.
+	value := d[0].value()
+	output = d[value]
 	if output.suit == joker {
-		return dp.cycle(n)
+		return dp.Advance(n)
 	}
 	return
 }
@@ -195,13 +212,47 @@ func (dp *deck) cycle(n int) (output *card) {
 // Keys the deck by performing a deck cycle for each key character.
 // Each cycle operation is extended with an additional count cut (based on
 // character index of current character).
-func (dp *deck) key(key []byte) {
+func (dp *Deck) key(key []byte) {
 	for _, c := range key {
 		n := int(c - 'A' + 1) // Calculate 1-based index of character
-		dp.cycle(n)
+		dp.Advance(n)
 	}
 }
 
+// deckFromOrder builds a deck from a caller-supplied ordering of card
+// values. Regular cards are numbered 1-52 following the clubs, diamonds,
+// hearts, spades suit order with A=1..K=13 within a suit; 53 and 54 denote
+// joker A and joker B respectively. Every value must appear exactly once.
+func deckFromOrder(order []int) (*Deck, error) {
+	size := suitSize*suitCount + 2
+	if len(order) != size {
+		return nil, fmt.Errorf("solitaire: deck ordering must contain %d cards", size)
+	}
+
+	seen := make(map[int]bool, size)
+	d := make(Deck, size)
+	for i, v := range order {
+		if seen[v] {
+			return nil, fmt.Errorf("solitaire: duplicate card %d in deck ordering", v)
+		}
+		seen[v] = true
+
+		switch {
+		case v >= 1 && v <= suitSize*suitCount:
+			s := suits[(v-1)/suitSize]
+			r := (v-1)%suitSize + 1
+			d[i] = &Card{s, r}
+		case v == suitSize*suitCount+1:
+			d[i] = &Card{joker, jokerA}
+		case v == suitSize*suitCount+2:
+			d[i] = &Card{joker, jokerB}
+		default:
+			return nil, fmt.Errorf("solitaire: invalid card value %d in deck ordering", v)
+		}
+	}
+	return &d, nil
+}
+
 // Pads data slice to nearest given multiple.
 func pad(data []byte, multiple int, padChar byte) []byte {
 	for len(data)%multiple > 0 {
@@ -233,15 +284,12 @@ func filter(data []byte) []byte {
 func Encrypt(plaintext, key []byte) []byte {
 	data := filter(plaintext)
 	data = pad(data, 5, 'X')
-	deck := newDeck()
 	output := make([]byte, len(data))
 
-	deck.key(filter(key))
-
+	ks := NewKeystream(key)
 	for i, c := range data {
-		card := deck.cycle(0)
 		n := c - 'A'
-		m := card.number()
+		m := ks.Next()
 		output[i] = 'A' + (n+m+1)%charSize
 	}
 
@@ -254,15 +302,12 @@ func Encrypt(plaintext, key []byte) []byte {
 // format for the encryption algorithm.
 func Decrypt(ciphertext, key []byte) []byte {
 	data := filter(ciphertext)
-	deck := newDeck()
 	output := make([]byte, len(data))
 
-	deck.key(filter(key))
-
+	ks := NewKeystream(key)
 	for i, c := range data {
-		card := deck.cycle(0)
 		n := c - 'A'
-		m := card.number()
+		m := ks.Next()
 		output[i] = 'A' + (n-m-1+charSize)%charSize
 	}
 
@@ -73,3 +73,160 @@ func ExampleDecrypt() {
 	fmt.Printf("%s\n", output)
 	// Output: SOLITAIREX
 }
+
+// Factory order: the deck ordering notation equivalent to NewDeck's default
+// ordering (clubs, diamonds, hearts, spades; A=1..K=13; then jokers A, B).
+const defaultDeckNotation = "AC 2C 3C 4C 5C 6C 7C 8C 9C 10C JC QC KC " +
+	"AD 2D 3D 4D 5D 6D 7D 8D 9D 10D JD QD KD " +
+	"AH 2H 3H 4H 5H 6H 7H 8H 9H 10H JH QH KH " +
+	"AS 2S 3S 4S 5S 6S 7S 8S 9S 10S JS QS KS A B"
+
+func TestDeckNotationRoundTrip(t *testing.T) {
+	d, err := NewDeckFromNotation(defaultDeckNotation)
+	if err != nil {
+		t.Fatalf("NewDeckFromNotation returned error: %v", err)
+	}
+	if d.String() != defaultDeckNotation {
+		t.Errorf("String() returns %q (expecting %q)", d.String(), defaultDeckNotation)
+	}
+
+	// A deck in default order should behave exactly like an unkeyed deck.
+	output := EncryptWithDeck([]byte("AAAAAAAAAAAAAAA"), d)
+	want := "EXKYIZSGEHUNTIQ"
+	if !bytes.Equal(output, []byte(want)) {
+		t.Errorf("EncryptWithDeck returns %q (expecting %q)", output, want)
+	}
+}
+
+func TestEncryptDecryptStringFidelity(t *testing.T) {
+	s := "Hello, world!"
+	opts := Options{Fidelity: true}
+
+	ct, token := EncryptString(s, []byte("cryptonomicon"), opts)
+	pt, err := DecryptString(ct, []byte("cryptonomicon"), token, opts)
+	if err != nil {
+		t.Fatalf("DecryptString returned error: %v", err)
+	}
+	if pt != s {
+		t.Errorf("round trip returns %q (expecting %q)", pt, s)
+	}
+}
+
+func TestEncryptDecryptStringNoFidelity(t *testing.T) {
+	ct, token := EncryptString("foo", []byte("bar"), Options{})
+	if token != "" {
+		t.Errorf("token = %q, expecting empty without Fidelity", token)
+	}
+
+	pt, err := DecryptString(ct, []byte("bar"), token, Options{})
+	if err != nil {
+		t.Fatalf("DecryptString returned error: %v", err)
+	}
+	if pt != "FOOXX" {
+		t.Errorf("DecryptString returns %q (expecting %q)", pt, "FOOXX")
+	}
+}
+
+func TestNewDeckFromNotationInvalid(t *testing.T) {
+	// A full 54-card deck in which KS is replaced by a second AC.
+	dup := string(bytes.Replace([]byte(defaultDeckNotation), []byte("KS"), []byte("AC"), 1))
+	if _, err := NewDeckFromNotation(dup); err == nil {
+		t.Error("NewDeckFromNotation accepted a deck with a duplicate card")
+	}
+	if _, err := NewDeckFromNotation("AC"); err == nil {
+		t.Error("NewDeckFromNotation accepted a deck missing cards")
+	}
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	enc, err := NewCipher([]byte("cryptonomicon"))
+	if err != nil {
+		t.Fatalf("NewCipher returned error: %v", err)
+	}
+	dec, err := NewDecrypter([]byte("cryptonomicon"))
+	if err != nil {
+		t.Fatalf("NewDecrypter returned error: %v", err)
+	}
+
+	pt := []byte("SOLITAIRE")
+	ct := make([]byte, len(pt))
+	enc.XORKeyStream(ct, pt)
+	if bytes.Equal(ct, pt) {
+		t.Fatalf("NewCipher didn't transform input")
+	}
+
+	out := make([]byte, len(ct))
+	dec.XORKeyStream(out, ct)
+	if !bytes.Equal(out, pt) {
+		t.Errorf("NewDecrypter(NewCipher(pt)) = %q, want %q", out, pt)
+	}
+}
+
+func TestCipherPassthrough(t *testing.T) {
+	enc, err := NewCipher([]byte("cryptonomicon"))
+	if err != nil {
+		t.Fatalf("NewCipher returned error: %v", err)
+	}
+	src := []byte("SO LITAIRE!")
+	dst := make([]byte, len(src))
+	enc.XORKeyStream(dst, src)
+	if dst[2] != ' ' || dst[len(dst)-1] != '!' {
+		t.Errorf("non-letter bytes not passed through: %q", dst)
+	}
+}
+
+func TestCipherPassthroughDisabled(t *testing.T) {
+	enc, err := NewCipher([]byte("cryptonomicon"), WithPassthrough(false))
+	if err != nil {
+		t.Fatalf("NewCipher returned error: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("XORKeyStream didn't panic on a non-letter byte with passthrough disabled")
+		}
+	}()
+	src := []byte("SO LITAIRE")
+	dst := make([]byte, len(src))
+	enc.XORKeyStream(dst, src)
+}
+
+func TestDeckSnapshotIsDeepCopy(t *testing.T) {
+	d := NewDeck()
+	before := d.Snapshot()
+
+	d.Advance(0)
+
+	after := d.Snapshot()
+	if bytes.Equal([]byte(fmt.Sprint(before)), []byte(fmt.Sprint(after))) {
+		t.Fatalf("deck didn't change after Advance; test is meaningless")
+	}
+	if fmt.Sprint(before) == fmt.Sprint(d.Snapshot()) {
+		t.Errorf("Snapshot aliases the live deck instead of copying it")
+	}
+	// Taking another snapshot right after must reproduce "after" exactly,
+	// since nothing advanced the deck in between.
+	if fmt.Sprint(after) != fmt.Sprint(d.Snapshot()) {
+		t.Errorf("Snapshot is not deterministic for an unchanged deck")
+	}
+}
+
+func TestKeystreamTrace(t *testing.T) {
+	var buf bytes.Buffer
+	ks := NewKeystream([]byte("foo"))
+	ks.Trace = &buf
+
+	want := ks.Next()
+
+	for _, step := range []string{"step 1", "step 2", "step 3", "step 4", "step 5"} {
+		if !bytes.Contains(buf.Bytes(), []byte(step)) {
+			t.Errorf("Trace output missing %q:\n%s", step, buf.String())
+		}
+	}
+
+	// Tracing must not change the actual keystream output.
+	untraced := NewKeystream([]byte("foo")).Next()
+	if want != untraced {
+		t.Errorf("Next() with Trace returns %d, want %d (same as without Trace)", want, untraced)
+	}
+}
@@ -0,0 +1,108 @@
+// Package solitairebreak implements ciphertext-only cryptanalysis of the
+// Solitaire cipher (see the parent solitaire package) by searching for a
+// passphrase whose decryption scores highest under an English-language
+// fitness function.
+//
+// Unlike the classical substitution and Vigenere ciphers this approach is
+// modeled on, Solitaire has no fixed per-position relationship between key
+// and ciphertext: each passphrase character re-keys the whole deck by
+// performing a deck cycle, so changing a single key character changes
+// every card drawn afterwards. There is no local fitness gradient for
+// hill-climbing to follow the way there is for, say, a Caesar shift. In
+// practice RecoverPassphrase/Search should be expected to do no better
+// than a random search over the keyspace; treat them as an experimental
+// best-effort heuristic and exploration tool, not a working passphrase
+// recovery tool. For keyspaces small enough to be practical, brute-force
+// enumeration (trying every candidate, or every Wordlist entry, and
+// keeping the one with the best Fitness) is likely to be both simpler and
+// more effective than the search in this package.
+package solitairebreak
+
+import "math"
+
+const quadgramLen = 4
+
+// monogramFreq holds the published relative frequency (in percent) of each
+// letter in general English text. These are the standard figures quoted in
+// cryptanalysis references (e.g. Friedman-style frequency tables).
+var monogramFreq = map[byte]float64{
+	'A': 8.2, 'B': 1.5, 'C': 2.8, 'D': 4.3, 'E': 12.7, 'F': 2.2,
+	'G': 2.0, 'H': 6.1, 'I': 7.0, 'J': 0.15, 'K': 0.77, 'L': 4.0,
+	'M': 2.4, 'N': 6.7, 'O': 7.5, 'P': 1.9, 'Q': 0.095, 'R': 6.0,
+	'S': 6.3, 'T': 9.1, 'U': 2.8, 'V': 0.98, 'W': 2.4, 'X': 0.15,
+	'Y': 2.0, 'Z': 0.074,
+}
+
+// commonBigrams lists English's most frequent letter pairs, in descending
+// order of frequency, as commonly published in cryptanalysis references
+// (e.g. "TH, HE, AN, IN, ER, ON, RE, ED, ND, ...").  bigramWeight uses rank
+// within this list as a stand-in for a transition probability: we don't
+// have a full corpus-derived digraph table available, so this is a coarse
+// approximation rather than a measured frequency.
+var commonBigrams = []string{
+	"TH", "HE", "AN", "IN", "ER", "ON", "RE", "ED", "ND", "HA",
+	"AT", "EN", "ES", "OF", "NT", "EA", "TI", "TO", "IO", "LE",
+	"IS", "OU", "AR", "AS", "DE", "RT", "VE", "CO", "ME", "DI",
+	"RA", "IC", "NE", "GE", "OR", "TE", "OM", "RI", "SE", "LI",
+	"ST", "CE", "NG", "AL", "LL", "SI", "HI", "RO", "WH", "WA",
+}
+
+var bigramRank map[string]int
+
+func init() {
+	bigramRank = make(map[string]int, len(commonBigrams))
+	for i, b := range commonBigrams {
+		bigramRank[b] = i
+	}
+}
+
+// bigramLogProb approximates log10 P(b | a) for consecutive letters a, b.
+// Pairs on the commonBigrams list get a probability that decreases with
+// rank; everything else falls back to a low floor.
+func bigramLogProb(a, b byte) float64 {
+	rank, ok := bigramRank[string([]byte{a, b})]
+	if !ok {
+		return -3.2 // floor: roughly as likely as an uncommon, but valid, pair
+	}
+	// Rank 0 (the most common pair, "TH") scores close to a typical single
+	// letter's log-probability; later ranks decay geometrically.
+	return -1.1 - float64(rank)*0.03
+}
+
+// Fitness scores text (assumed to be upper-case A-Z only, as produced by
+// solitaire.Decrypt) by how English-like it looks.
+//
+// There is no literal quadgram frequency corpus embedded in this package --
+// that would need on the order of 10^5 entries to cover English well, which
+// isn't practical to hand-author or vendor here. Instead, Fitness
+// approximates a quadgram log-probability as a first-order Markov chain:
+// the log-probability of the first letter (from published English
+// monogram frequencies) plus the log-probability of each subsequent letter
+// given the one before it (from a short list of common English bigrams),
+// summed over each successive, overlapping 4-letter window. Higher
+// (less negative) scores indicate more English-like text. This is a much
+// cruder signal than a real quadgram table and should be treated as a
+// coarse heuristic, not a calibrated language model.
+func Fitness(text []byte) float64 {
+	if len(text) < quadgramLen {
+		return 0
+	}
+
+	var score float64
+	for i := 0; i+quadgramLen <= len(text); i++ {
+		q := text[i : i+quadgramLen]
+		score += monogramLogProb(q[0])
+		for j := 1; j < quadgramLen; j++ {
+			score += bigramLogProb(q[j-1], q[j])
+		}
+	}
+	return score
+}
+
+func monogramLogProb(c byte) float64 {
+	f, ok := monogramFreq[c]
+	if !ok || f <= 0 {
+		return -3
+	}
+	return math.Log10(f / 100)
+}
@@ -0,0 +1,19 @@
+package solitairebreak
+
+import "testing"
+
+func TestFitnessPrefersEnglish(t *testing.T) {
+	english := []byte("THEQUICKBROWNFOXJUMPSOVERTHELAZYDOG")
+	random := []byte("QXZJVKWPYBFHMGCLNRSTUEIOADQXZJVKWPYB")
+
+	if Fitness(english) <= Fitness(random) {
+		t.Errorf("Fitness(%q) = %v, want greater than Fitness(%q) = %v",
+			english, Fitness(english), random, Fitness(random))
+	}
+}
+
+func TestFitnessShortText(t *testing.T) {
+	if got := Fitness([]byte("AB")); got != 0 {
+		t.Errorf("Fitness of text shorter than a quadgram = %v, want 0", got)
+	}
+}
@@ -0,0 +1,210 @@
+package solitairebreak
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/tvdburgt/solitaire"
+)
+
+const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Options configures RecoverPassphrase and Search.
+type Options struct {
+	// KeyLen is the length of the initial candidate passphrase. When
+	// VariableLength is set, a candidate can shrink below KeyLen, down to a
+	// single character.
+	KeyLen int
+
+	// VariableLength allows mutation to occasionally insert or delete a
+	// character, instead of only substituting one.
+	VariableLength bool
+
+	// Restarts is the number of additional hill-climbs to run (on top of
+	// the first) from a fresh seed, keeping the best result across all of
+	// them. Zero means a single run.
+	Restarts int
+
+	// Steps is the number of mutation attempts performed per restart.
+	Steps int
+
+	// Temperature is the initial annealing temperature. Higher values
+	// accept more worsening moves early in the search. Zero selects a
+	// default of 10.
+	Temperature float64
+
+	// Cooling is the per-step multiplicative cooling rate applied to
+	// Temperature, e.g. 0.999. Zero selects a default of 0.9995.
+	Cooling float64
+
+	// Wordlist, if non-empty, seeds every restart's initial passphrase
+	// with a random entry instead of a random string of KeyLen letters.
+	Wordlist []string
+
+	// Rand supplies randomness for the search. If nil, a source seeded
+	// from the runtime is used.
+	Rand *rand.Rand
+}
+
+// Candidate is a passphrase considered during a search, together with the
+// plaintext it produces and its fitness score.
+type Candidate struct {
+	Key       string
+	Plaintext []byte
+	Score     float64
+}
+
+// RecoverPassphrase runs Search to completion and returns the best
+// candidate found.
+//
+// See the package doc for an important caveat: because a single passphrase
+// character change re-keys the entire deck, this hill-climbing search has
+// no meaningful gradient to follow and is not expected to reliably recover
+// the real passphrase, even given a generous step/restart budget.
+func RecoverPassphrase(ct []byte, opts Options) (key string, plaintext []byte, score float64) {
+	var best Candidate
+	for c := range Search(context.Background(), ct, opts) {
+		best = c
+	}
+	return best.Key, best.Plaintext, best.Score
+}
+
+// Search attempts to recover the Solitaire passphrase that produced ct by
+// hill-climbing / simulated annealing over candidate passphrases, scoring
+// each decryption with Fitness. It starts from a random (or, with
+// opts.Wordlist, dictionary-seeded) passphrase of length opts.KeyLen, and at
+// each step mutates one character -- a random letter substitution, or,
+// when opts.VariableLength is set, an occasional insertion or deletion --
+// decrypts ct with the module's existing solitaire.Decrypt, and accepts the
+// move if it improves the score or, with probability exp(delta/T) at the
+// current temperature T, if it doesn't.
+//
+// Search is context-cancelable: it returns promptly once ctx is done. Every
+// new best-so-far candidate (across all opts.Restarts+1 runs) is sent on
+// the returned channel, which is closed when the search ends.
+//
+// Caveat: Solitaire's keystream is a chaotic function of the passphrase --
+// re-keying the deck with a single different character changes every
+// subsequent output card, so single-character mutation has no local
+// fitness gradient to climb. This search is best treated as an
+// experimental heuristic, not a dependable recovery tool; see the package
+// doc.
+func Search(ctx context.Context, ct []byte, opts Options) <-chan Candidate {
+	out := make(chan Candidate)
+
+	go func() {
+		defer close(out)
+
+		rnd := opts.Rand
+		if rnd == nil {
+			rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+
+		temperature := opts.Temperature
+		if temperature <= 0 {
+			temperature = 10
+		}
+		cooling := opts.Cooling
+		if cooling <= 0 {
+			cooling = 0.9995
+		}
+		steps := opts.Steps
+		if steps <= 0 {
+			steps = 20000
+		}
+
+		var best Candidate
+		best.Score = math.Inf(-1)
+
+		for run := 0; run <= opts.Restarts; run++ {
+			key := seedKey(opts, rnd)
+			pt := solitaire.Decrypt(ct, []byte(key))
+			score := Fitness(pt)
+			T := temperature
+
+			if score > best.Score {
+				best = Candidate{Key: key, Plaintext: pt, Score: score}
+				select {
+				case out <- best:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			for step := 0; step < steps; step++ {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				candidate := mutate(key, opts.VariableLength, rnd)
+				candidatePt := solitaire.Decrypt(ct, []byte(candidate))
+				candidateScore := Fitness(candidatePt)
+
+				if candidateScore > score ||
+					rnd.Float64() < math.Exp((candidateScore-score)/T) {
+					key, pt, score = candidate, candidatePt, candidateScore
+
+					if score > best.Score {
+						best = Candidate{Key: key, Plaintext: pt, Score: score}
+						select {
+						case out <- best:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+
+				T *= cooling
+			}
+		}
+	}()
+
+	return out
+}
+
+// seedKey produces the initial passphrase for a single restart.
+func seedKey(opts Options, rnd *rand.Rand) string {
+	if len(opts.Wordlist) > 0 {
+		return opts.Wordlist[rnd.Intn(len(opts.Wordlist))]
+	}
+
+	n := opts.KeyLen
+	if n <= 0 {
+		n = 8
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[rnd.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+// mutate returns a copy of key with one character changed: a substitution,
+// or, when variableLength is set, an occasional insertion or deletion.
+func mutate(key string, variableLength bool, rnd *rand.Rand) string {
+	b := []byte(key)
+
+	if variableLength {
+		switch {
+		case rnd.Intn(10) == 0 && len(b) > 1: // delete
+			i := rnd.Intn(len(b))
+			return string(append(b[:i:i], b[i+1:]...))
+		case rnd.Intn(10) == 0: // insert
+			i := rnd.Intn(len(b) + 1)
+			c := alphabet[rnd.Intn(len(alphabet))]
+			out := make([]byte, 0, len(b)+1)
+			out = append(out, b[:i]...)
+			out = append(out, c)
+			out = append(out, b[i:]...)
+			return string(out)
+		}
+	}
+
+	i := rnd.Intn(len(b))
+	b[i] = alphabet[rnd.Intn(len(alphabet))]
+	return string(b)
+}
@@ -0,0 +1,25 @@
+package solitairebreak
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tvdburgt/solitaire"
+)
+
+func TestSearchFindsImprovingCandidates(t *testing.T) {
+	ct := solitaire.Encrypt([]byte("THEQUICKBROWNFOXJUMPSOVERTHELAZYDOG"), []byte("KEY"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	last := Candidate{Score: math.Inf(-1)}
+	for c := range Search(ctx, ct, Options{KeyLen: 3, Steps: 5000}) {
+		if c.Score < last.Score {
+			t.Errorf("Search sent a non-improving candidate: %v after %v", c.Score, last.Score)
+		}
+		last = c
+	}
+}